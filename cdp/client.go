@@ -0,0 +1,219 @@
+// Package cdp is a minimal, reusable Chrome DevTools Protocol websocket client. It replaces the
+// previous pattern of hand-building "{"id": 1, ...}" strings and reading exactly one reply per
+// connection, which broke as soon as the browser emitted an event frame ahead of the reply.
+package cdp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// request is a single outgoing CDP command frame
+type request struct {
+	ID        int             `json:"id"`
+	SessionID string          `json:"sessionId,omitempty"`
+	Method    string          `json:"method"`
+	Params    json.RawMessage `json:"params,omitempty"`
+}
+
+// Error is the CDP error object a command's reply carries in place of a result when it fails
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("cdp error %d: %s", e.Code, e.Message)
+}
+
+// response is a single incoming CDP reply frame, demultiplexed by ID
+type response struct {
+	ID        int             `json:"id"`
+	SessionID string          `json:"sessionId,omitempty"`
+	Result    json.RawMessage `json:"result,omitempty"`
+	Error     *Error          `json:"error,omitempty"`
+}
+
+// Event is a CDP event frame: it carries a method but no id and arrives unsolicited, e.g.
+// Target.attachedToTarget or Network.responseReceivedExtraInfo
+type Event struct {
+	SessionID string          `json:"sessionId,omitempty"`
+	Method    string          `json:"method"`
+	Params    json.RawMessage `json:"params,omitempty"`
+}
+
+// Client is a CDP websocket client with a monotonically incrementing request ID, a background
+// read loop that demultiplexes replies by ID, and surfaced CDP errors instead of log.Fatalf.
+// Commands are scoped to a Target session via the flat "sessionId" field modern Chromium
+// accepts directly on the request frame, rather than wrapping them in
+// Target.sendMessageToTarget.
+type Client struct {
+	conn *websocket.Conn
+
+	mu      sync.Mutex
+	nextID  int
+	pending map[int]chan response
+	closed  bool
+
+	// Events receives every frame that isn't a reply to a pending command
+	Events chan Event
+}
+
+// Dial connects to a CDP websocket endpoint and starts demultiplexing incoming frames
+func Dial(ctx context.Context, url string) (*Client, error) {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial websocket: %w", err)
+	}
+
+	// Set read limit
+	conn.SetReadLimit(10 * 1024 * 1024) // 10 MB
+
+	c := &Client{
+		conn:    conn,
+		nextID:  1,
+		pending: make(map[int]chan response),
+		Events:  make(chan Event, 32),
+	}
+
+	go c.readLoop()
+
+	return c, nil
+}
+
+// Close closes the underlying websocket connection
+func (c *Client) Close() error {
+	c.mu.Lock()
+	c.closed = true
+	c.mu.Unlock()
+	return c.conn.Close()
+}
+
+func (c *Client) readLoop() {
+	defer close(c.Events)
+
+	for {
+		_, raw, err := c.conn.ReadMessage()
+		if err != nil {
+			c.mu.Lock()
+			for id, ch := range c.pending {
+				close(ch)
+				delete(c.pending, id)
+			}
+			c.mu.Unlock()
+			return
+		}
+
+		var frame struct {
+			ID int `json:"id"`
+		}
+		if err := json.Unmarshal(raw, &frame); err != nil {
+			continue
+		}
+
+		if frame.ID == 0 {
+			var event Event
+			if err := json.Unmarshal(raw, &event); err == nil {
+				select {
+				case c.Events <- event:
+				default:
+				}
+			}
+			continue
+		}
+
+		var resp response
+		if err := json.Unmarshal(raw, &resp); err != nil {
+			continue
+		}
+
+		c.mu.Lock()
+		ch, ok := c.pending[resp.ID]
+		if ok {
+			delete(c.pending, resp.ID)
+		}
+		c.mu.Unlock()
+
+		if ok {
+			ch <- resp
+			close(ch)
+		}
+	}
+}
+
+// Call issues a CDP command and blocks until the matching reply arrives, returning the raw
+// result bytes or the CDP error surfaced in the reply
+func (c *Client) Call(method string, params interface{}) (json.RawMessage, error) {
+	return c.call("", method, params)
+}
+
+// CallSession issues a CDP command scoped to a Target session, the way commands are routed to a
+// particular BrowserContext over the browser-wide endpoint
+func (c *Client) CallSession(sessionID string, method string, params interface{}) (json.RawMessage, error) {
+	return c.call(sessionID, method, params)
+}
+
+func (c *Client) call(sessionID string, method string, params interface{}) (json.RawMessage, error) {
+	var paramsJSON json.RawMessage
+	if params != nil {
+		encoded, err := json.Marshal(params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal params: %w", err)
+		}
+		paramsJSON = encoded
+	}
+
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil, fmt.Errorf("client is closed")
+	}
+	id := c.nextID
+	c.nextID++
+	ch := make(chan response, 1)
+	c.pending[id] = ch
+	c.mu.Unlock()
+
+	req := request{ID: id, SessionID: sessionID, Method: method, Params: paramsJSON}
+	reqJSON, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	if err := c.conn.WriteMessage(websocket.TextMessage, reqJSON); err != nil {
+		return nil, fmt.Errorf("failed to send message: %w", err)
+	}
+
+	resp, ok := <-ch
+	if !ok {
+		return nil, fmt.Errorf("connection closed before response to %s", method)
+	}
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+
+	return resp.Result, nil
+}
+
+// WaitForEvent blocks until an event matching method arrives or the timeout elapses
+func (c *Client) WaitForEvent(method string, timeout time.Duration) (Event, error) {
+	deadline := time.After(timeout)
+	for {
+		select {
+		case event, ok := <-c.Events:
+			if !ok {
+				return Event{}, fmt.Errorf("connection closed while waiting for %s", method)
+			}
+			if event.Method == method {
+				return event, nil
+			}
+		case <-deadline:
+			return Event{}, fmt.Errorf("timed out waiting for %s", method)
+		}
+	}
+}
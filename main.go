@@ -7,12 +7,16 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"net/http/cookiejar"
+	"net/url"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/akamensky/argparse"
-	"github.com/gorilla/websocket"
+
+	"github.com/jeremybuis/WhiteChocolateMacademiaNut/cdp"
 )
 
 // DebugData is JSON structure returned by Chromium
@@ -25,16 +29,17 @@ type DebugData struct {
 	PageType             string `json:"type"`
 	URL                  string `json:"url"`
 	WebSocketDebuggerURL string `json:"webSocketDebuggerUrl"`
+	BrowserContextID     string `json:"browserContextId"`
 }
 
-// WebsocketResponseRoot is the raw response from Chromium websocket
-type WebsocketResponseRoot struct {
-	ID     int                     `json:"id"`
-	Result WebsocketResponseNested `json:"result"`
+// DebugVersion is the JSON structure returned by Chromium's /json/version endpoint, used to
+// reach the browser-wide CDP session rather than any single tab/extension target
+type DebugVersion struct {
+	WebSocketDebuggerURL string `json:"webSocketDebuggerUrl"`
 }
 
-// WebsocketResponseNested is the object within the raw response from Chromium websocket
-type WebsocketResponseNested struct {
+// CookiesResult is the Result payload of Network.getAllCookies/Storage.getCookies
+type CookiesResult struct {
 	Cookies []Cookie `json:"cookies"`
 }
 
@@ -53,6 +58,34 @@ type Cookie struct {
 	Priority string  `json:"priority"`
 }
 
+// ToHTTPCookie converts a Cookie into the net/http equivalent so it can be loaded into a
+// net/http/cookiejar.Jar and replayed against a URL outside the browser
+func (c Cookie) ToHTTPCookie() *http.Cookie {
+	cookie := &http.Cookie{
+		Name:     c.Name,
+		Value:    c.Value,
+		Domain:   c.Domain,
+		Path:     c.Path,
+		Secure:   c.Secure,
+		HttpOnly: c.HTTPOnly,
+	}
+
+	if !c.Session {
+		cookie.Expires = time.Unix(int64(c.Expires), 0)
+	}
+
+	switch strings.ToLower(c.SameSite) {
+	case "strict":
+		cookie.SameSite = http.SameSiteStrictMode
+	case "lax":
+		cookie.SameSite = http.SameSiteLaxMode
+	case "none":
+		cookie.SameSite = http.SameSiteNoneMode
+	}
+
+	return cookie
+}
+
 // LightCookie is a JSON structure for the cookie with only the name, value, domain, path, and (modified) expires fields
 type LightCookie struct {
 	Name    string  `json:"name"`
@@ -62,6 +95,121 @@ type LightCookie struct {
 	Expires float64 `json:"expirationDate"`
 }
 
+// NetscapeCookieParam mirrors the Network.setCookies CookieParam fields produced when
+// converting a Netscape/Mozilla cookies.txt entry for loading into Chromium. Expires is a
+// pointer so a Netscape "0" (the format's session-cookie convention) can be omitted from the
+// marshaled param entirely rather than sent as a literal 0, which CDP treats as "expired in 1970"
+// rather than "session cookie"
+type NetscapeCookieParam struct {
+	Name     string   `json:"name"`
+	Value    string   `json:"value"`
+	Domain   string   `json:"domain"`
+	Path     string   `json:"path"`
+	Secure   bool     `json:"secure"`
+	HTTPOnly bool     `json:"httpOnly"`
+	Expires  *float64 `json:"expires,omitempty"`
+}
+
+// netscapeHeader is the comment line Chromium, curl, and wget all recognize at the top of a
+// Netscape/Mozilla cookies.txt file
+const netscapeHeader = "# Netscape HTTP Cookie File"
+
+// isNetscapeFormat inspects a cookie file to determine whether it is the tab-delimited
+// Netscape format rather than this tool's native JSON format
+func isNetscapeFormat(content []byte) bool {
+	trimmed := strings.TrimSpace(string(content))
+	if strings.HasPrefix(trimmed, "#") && strings.Contains(trimmed, "Netscape") {
+		return true
+	}
+
+	firstLine := strings.SplitN(trimmed, "\n", 2)[0]
+	return strings.Count(firstLine, "\t") == 6
+}
+
+// FormatNetscapeCookie renders a Cookie as a single tab-separated Netscape cookies.txt line,
+// prefixing the domain with "#HttpOnly_" the way curl and wget do for HttpOnly cookies, and
+// writing the Netscape convention of "0" for session cookies rather than Chromium's own -1
+func FormatNetscapeCookie(c Cookie) string {
+	domain := c.Domain
+	includeSubdomains := "FALSE"
+	if strings.HasPrefix(domain, ".") {
+		includeSubdomains = "TRUE"
+	}
+	if c.HTTPOnly {
+		domain = "#HttpOnly_" + domain
+	}
+
+	secure := "FALSE"
+	if c.Secure {
+		secure = "TRUE"
+	}
+
+	expires := int64(c.Expires)
+	if c.Session {
+		expires = 0
+	}
+
+	return strings.Join([]string{
+		domain,
+		includeSubdomains,
+		c.Path,
+		secure,
+		strconv.FormatInt(expires, 10),
+		c.Name,
+		c.Value,
+	}, "\t")
+}
+
+// ParseNetscapeCookies converts the tab-separated Netscape/Mozilla cookies.txt format into
+// Network.setCookies CookieParam-compatible structs, mapping the domain flag to the leading-dot
+// HostOnly convention Chromium expects and the "#HttpOnly_" domain prefix to the HTTPOnly field
+func ParseNetscapeCookies(content []byte) []NetscapeCookieParam {
+	var params []NetscapeCookieParam
+
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if line == "" || (strings.HasPrefix(line, "#") && !strings.HasPrefix(line, "#HttpOnly_")) {
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		if len(fields) != 7 {
+			continue
+		}
+
+		domain := fields[0]
+		httpOnly := strings.HasPrefix(domain, "#HttpOnly_")
+		if httpOnly {
+			domain = strings.TrimPrefix(domain, "#HttpOnly_")
+		}
+
+		includeSubdomains := fields[1] == "TRUE"
+		domain = strings.TrimPrefix(domain, ".")
+		if includeSubdomains {
+			domain = "." + domain
+		}
+
+		var expires *float64
+		if fields[4] != "0" {
+			if parsed, err := strconv.ParseFloat(fields[4], 64); err == nil {
+				expires = &parsed
+			}
+		}
+
+		params = append(params, NetscapeCookieParam{
+			Name:     fields[5],
+			Value:    fields[6],
+			Domain:   domain,
+			Path:     fields[2],
+			Secure:   fields[3] == "TRUE",
+			HTTPOnly: httpOnly,
+			Expires:  expires,
+		})
+	}
+
+	return params
+}
+
 // GetDebugData interacts with Chromium debug port to obtain the JSON response of open tabs/installed extensions
 func GetDebugData(debugPort string) []DebugData {
 	var debugURL = "http://localhost:" + debugPort + "/json"
@@ -84,61 +232,163 @@ func GetDebugData(debugPort string) []DebugData {
 	return debugList
 }
 
-// PrintDebugData takes the JSON response from Chromium and prints open tabs and installed extensions
-func PrintDebugData(debugList []DebugData, grep string) {
-	grepFlag := len(grep) > 0
+// GetDebugVersion interacts with Chromium's /json/version endpoint to obtain the browser-wide
+// webSocketDebuggerUrl, which is required to attach outside of any single tab/context
+func GetDebugVersion(debugPort string) DebugVersion {
+	var versionURL = "http://localhost:" + debugPort + "/json/version"
+	resp, err := http.Get(versionURL)
+	if err != nil {
+		log.Fatalf("Failed to get debug version: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		log.Fatalf("Failed to read response body: %v", err)
+	}
+
+	var debugVersion DebugVersion
+	if err := json.Unmarshal(body, &debugVersion); err != nil {
+		log.Fatalf("Failed to unmarshal JSON: %v", err)
+	}
+
+	return debugVersion
+}
+
+// SelectTarget picks the DebugData entry to operate on: --target matches a target's id and
+// --target-url matches a substring of its URL. With neither provided it falls back to the
+// first entry Chromium returns, matching the tool's previous hardcoded behavior
+func SelectTarget(debugList []DebugData, target string, targetURL string) DebugData {
+	if target == "" && targetURL == "" {
+		return debugList[0]
+	}
 
 	for _, value := range debugList {
-		if !grepFlag || strings.Contains(value.Title, grep) || strings.Contains(value.URL, grep) {
-			fmt.Printf("Title: %s\n", value.Title)
-			fmt.Printf("Type: %s\n", value.PageType)
-			fmt.Printf("URL: %s\n", value.URL)
-			fmt.Printf("WebSocket Debugger URL: %s\n\n", value.WebSocketDebuggerURL)
+		if target != "" && value.ID == target {
+			return value
+		}
+		if targetURL != "" && strings.Contains(value.URL, targetURL) {
+			return value
 		}
 	}
-}
 
-// DumpCookies interacts with the webSocketDebuggerUrl to obtain Chromium cookies
-func DumpCookies(debugList []DebugData, format string, grep string) {
-	grepFlag := len(grep) > 0
-	websocketURL := debugList[0].WebSocketDebuggerURL
+	log.Fatalf("No target matched --target %q / --target-url %q", target, targetURL)
+	return DebugData{}
+}
 
+// dialTarget opens a cdp.Client against a tab/extension target's own websocket endpoint
+func dialTarget(target DebugData) *cdp.Client {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	conn, _, err := websocket.DefaultDialer.DialContext(ctx, websocketURL, nil)
+	client, err := cdp.Dial(ctx, target.WebSocketDebuggerURL)
 	if err != nil {
 		log.Fatalf("Failed to dial websocket: %v", err)
 	}
-	defer conn.Close()
 
-	// Set read limit
-	conn.SetReadLimit(10 * 1024 * 1024) // 10 MB
+	return client
+}
+
+// AttachToBrowserTarget dials the browser-wide CDP websocket (from /json/version) and attaches
+// to it via Target.attachToBrowserTarget, returning the client and the resulting sessionId so
+// callers can issue Storage.* commands scoped by BrowserContextId
+func AttachToBrowserTarget(debugPort string) (*cdp.Client, string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 
-	message := `{"id": 1, "method":"Network.getAllCookies"}`
-	if err := conn.WriteMessage(websocket.TextMessage, []byte(message)); err != nil {
-		log.Fatalf("Failed to send message: %v", err)
+	client, err := cdp.Dial(ctx, GetDebugVersion(debugPort).WebSocketDebuggerURL)
+	if err != nil {
+		log.Fatalf("Failed to dial browser websocket: %v", err)
 	}
 
-	_, rawResponse, err := conn.ReadMessage()
+	result, err := client.Call("Target.attachToBrowserTarget", nil)
 	if err != nil {
-		log.Fatalf("Failed to read response: %v", err)
+		log.Fatalf("Failed to attach to browser target: %v", err)
 	}
 
-	var websocketResponseRoot WebsocketResponseRoot
-	if err := json.Unmarshal(rawResponse, &websocketResponseRoot); err != nil {
+	var attached struct {
+		SessionID string `json:"sessionId"`
+	}
+	if err := json.Unmarshal(result, &attached); err != nil {
 		log.Fatalf("Failed to unmarshal JSON: %v", err)
 	}
 
+	return client, attached.SessionID
+}
+
+// PrintDebugData takes the JSON response from Chromium and prints open tabs and installed extensions
+func PrintDebugData(debugList []DebugData, grep string) {
+	grepFlag := len(grep) > 0
+
+	for _, value := range debugList {
+		if !grepFlag || strings.Contains(value.Title, grep) || strings.Contains(value.URL, grep) {
+			fmt.Printf("Title: %s\n", value.Title)
+			fmt.Printf("Type: %s\n", value.PageType)
+			fmt.Printf("URL: %s\n", value.URL)
+			fmt.Printf("WebSocket Debugger URL: %s\n\n", value.WebSocketDebuggerURL)
+		}
+	}
+}
+
+// GetCookies fetches the full cookie jar for the selected target. If the target belongs to a
+// non-default browser context (e.g. Incognito), the cookies are instead fetched via
+// Storage.getCookies scoped by BrowserContextId over the browser-wide session, since
+// Network.getAllCookies on the tab's own session cannot see them
+func GetCookies(debugPort string, target DebugData) (CookiesResult, json.RawMessage) {
+	var rawResult json.RawMessage
+	if target.BrowserContextID != "" {
+		client, sessionID := AttachToBrowserTarget(debugPort)
+		defer client.Close()
+
+		result, err := client.CallSession(sessionID, "Storage.getCookies", map[string]string{"browserContextId": target.BrowserContextID})
+		if err != nil {
+			log.Fatalf("Failed to get cookies: %v", err)
+		}
+		rawResult = result
+	} else {
+		client := dialTarget(target)
+		defer client.Close()
+
+		result, err := client.Call("Network.getAllCookies", nil)
+		if err != nil {
+			log.Fatalf("Failed to get cookies: %v", err)
+		}
+		rawResult = result
+	}
+
+	var cookiesResult CookiesResult
+	if err := json.Unmarshal(rawResult, &cookiesResult); err != nil {
+		log.Fatalf("Failed to unmarshal JSON: %v", err)
+	}
+
+	return cookiesResult, rawResult
+}
+
+// DumpCookies interacts with the webSocketDebuggerUrl to obtain Chromium cookies
+func DumpCookies(debugPort string, target DebugData, format string, grep string) {
+	grepFlag := len(grep) > 0
+
+	cookiesResult, rawResult := GetCookies(debugPort, target)
+
 	if format == "raw" {
-		fmt.Printf("%s\n", rawResponse)
+		fmt.Printf("%s\n", rawResult)
+		return
+	}
+
+	if format == "netscape" {
+		fmt.Println(netscapeHeader)
+		for _, value := range cookiesResult.Cookies {
+			if !grepFlag || strings.Contains(value.Name, grep) || strings.Contains(value.Domain, grep) {
+				fmt.Println(FormatNetscapeCookie(value))
+			}
+		}
 		return
 	}
 
 	if format == "modified" {
 		var lightCookieList []LightCookie
 
-		for _, value := range websocketResponseRoot.Result.Cookies {
+		for _, value := range cookiesResult.Cookies {
 			if !grepFlag || strings.Contains(value.Name, grep) || strings.Contains(value.Domain, grep) {
 				lightCookie := LightCookie{
 					Name:    value.Name,
@@ -160,7 +410,7 @@ func DumpCookies(debugList []DebugData, format string, grep string) {
 		return
 	}
 
-	for _, value := range websocketResponseRoot.Result.Cookies {
+	for _, value := range cookiesResult.Cookies {
 		if !grepFlag || strings.Contains(value.Name, grep) || strings.Contains(value.Domain, grep) {
 			fmt.Printf("name: %s\n", value.Name)
 			fmt.Printf("value: %s\n", value.Value)
@@ -177,50 +427,342 @@ func DumpCookies(debugList []DebugData, format string, grep string) {
 	}
 }
 
-func ClearCookies(debugList []DebugData) {
-	websocketURL := debugList[0].WebSocketDebuggerURL
+// ClearCookies wipes the entire cookie jar. For a target scoped to a non-default browser
+// context, this is done via Storage.clearCookies with the BrowserContextId; otherwise it uses
+// Network.clearBrowserCookies on the target's own session
+func ClearCookies(debugPort string, target DebugData) {
+	if target.BrowserContextID != "" {
+		client, sessionID := AttachToBrowserTarget(debugPort)
+		defer client.Close()
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+		if _, err := client.CallSession(sessionID, "Storage.clearCookies", map[string]string{"browserContextId": target.BrowserContextID}); err != nil {
+			log.Fatalf("Failed to clear cookies: %v", err)
+		}
+		return
+	}
 
-	conn, _, err := websocket.DefaultDialer.DialContext(ctx, websocketURL, nil)
-	if err != nil {
-		log.Fatalf("Failed to dial websocket: %v", err)
+	client := dialTarget(target)
+	defer client.Close()
+
+	if _, err := client.Call("Network.clearBrowserCookies", nil); err != nil {
+		log.Fatalf("Failed to clear cookies: %v", err)
+	}
+}
+
+// DeleteCookies issues Network.deleteCookies, scoping the deletion to whichever of the name,
+// domain, path, and url selectors were provided so individual cookies can be targeted instead
+// of wiping the entire jar
+func DeleteCookies(target DebugData, name string, domain string, path string, url string) {
+	client := dialTarget(target)
+	defer client.Close()
+
+	params := map[string]string{"name": name}
+	if domain != "" {
+		params["domain"] = domain
+	}
+	if path != "" {
+		params["path"] = path
+	}
+	if url != "" {
+		params["url"] = url
+	}
+
+	if _, err := client.Call("Network.deleteCookies", params); err != nil {
+		log.Fatalf("Failed to delete cookies: %v", err)
+	}
+}
+
+// ParseSetCookieSpec parses a "name=value;domain=...;path=...;expires=..." spec string into
+// Network.setCookie CDP params, treating the first segment as the mandatory name/value pair
+// and subsequent segments as cookie attributes
+func ParseSetCookieSpec(spec string) map[string]interface{} {
+	params := map[string]interface{}{}
+
+	for i, part := range strings.Split(spec, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		key, value := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+
+		if i == 0 {
+			params["name"] = key
+			params["value"] = value
+			continue
+		}
+
+		switch strings.ToLower(key) {
+		case "domain":
+			params["domain"] = value
+		case "path":
+			params["path"] = value
+		case "url":
+			params["url"] = value
+		case "expires":
+			if expires, err := strconv.ParseFloat(value, 64); err == nil {
+				params["expires"] = expires
+			}
+		case "secure":
+			params["secure"] = value == "true"
+		case "httponly":
+			params["httpOnly"] = value == "true"
+		case "samesite":
+			params["sameSite"] = value
+		}
 	}
-	defer conn.Close()
 
-	// Set read limit
-	conn.SetReadLimit(10 * 1024 * 1024) // 10 MB
+	return params
+}
+
+// SetCookie issues a single Network.setCookie command built from a "--set" spec string, letting
+// operators rotate or inject one cookie without crafting a JSON file
+func SetCookie(target DebugData, spec string) {
+	client := dialTarget(target)
+	defer client.Close()
 
-	message := `{"id": 1, "method": "Network.clearBrowserCookies"}`
-	if err := conn.WriteMessage(websocket.TextMessage, []byte(message)); err != nil {
-		log.Fatalf("Failed to send message: %v", err)
+	if _, err := client.Call("Network.setCookie", ParseSetCookieSpec(spec)); err != nil {
+		log.Fatalf("Failed to set cookie: %v", err)
 	}
 }
 
-func LoadCookies(debugList []DebugData, load string) {
+// LoadCookies interacts with the webSocketDebuggerUrl to load cookies into Chromium. For a
+// target scoped to a non-default browser context, this is done via Storage.setCookies with the
+// BrowserContextId; otherwise it uses Network.setCookies on the target's own session
+func LoadCookies(debugPort string, target DebugData, load string) {
 	content, err := os.ReadFile(load)
 	if err != nil {
 		log.Fatalf("Failed to read file: %v", err)
 	}
 
-	websocketURL := debugList[0].WebSocketDebuggerURL
+	if isNetscapeFormat(content) {
+		params := ParseNetscapeCookies(content)
+		content, err = json.Marshal(params)
+		if err != nil {
+			log.Fatalf("Failed to marshal Netscape cookies: %v", err)
+		}
+	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+	var cookies json.RawMessage = content
+
+	if target.BrowserContextID != "" {
+		client, sessionID := AttachToBrowserTarget(debugPort)
+		defer client.Close()
+
+		params := map[string]interface{}{"cookies": cookies, "browserContextId": target.BrowserContextID}
+		if _, err := client.CallSession(sessionID, "Storage.setCookies", params); err != nil {
+			log.Fatalf("Failed to load cookies: %v", err)
+		}
+		return
+	}
 
-	conn, _, err := websocket.DefaultDialer.DialContext(ctx, websocketURL, nil)
+	client := dialTarget(target)
+	defer client.Close()
+
+	if _, err := client.Call("Network.setCookies", map[string]interface{}{"cookies": cookies}); err != nil {
+		log.Fatalf("Failed to load cookies: %v", err)
+	}
+}
+
+// ReplayCookies loads the cookies dumped from the selected target into a
+// net/http/cookiejar.Jar and performs an authenticated GET against replayURL, printing the
+// response body. This closes the loop between grabbing cookies from a live Chromium session
+// and using them from Go without shuffling JSON into curl by hand
+func ReplayCookies(debugPort string, target DebugData, replayURL string) {
+	cookiesResult, _ := GetCookies(debugPort, target)
+
+	parsedURL, err := url.Parse(replayURL)
 	if err != nil {
-		log.Fatalf("Failed to dial websocket: %v", err)
+		log.Fatalf("Failed to parse replay URL: %v", err)
 	}
-	defer conn.Close()
 
-	// Set read limit
-	conn.SetReadLimit(10 * 1024 * 1024) // 10 MB
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		log.Fatalf("Failed to create cookie jar: %v", err)
+	}
 
-	message := fmt.Sprintf(`{"id": 1, "method":"Network.setCookies", "params":{"cookies":%s}}`, content)
-	if err := conn.WriteMessage(websocket.TextMessage, []byte(message)); err != nil {
-		log.Fatalf("Failed to send message: %v", err)
+	var httpCookies []*http.Cookie
+	for _, value := range cookiesResult.Cookies {
+		httpCookies = append(httpCookies, value.ToHTTPCookie())
+	}
+	jar.SetCookies(parsedURL, httpCookies)
+
+	httpClient := &http.Client{Jar: jar}
+	resp, err := httpClient.Get(replayURL)
+	if err != nil {
+		log.Fatalf("Failed to perform replay request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		log.Fatalf("Failed to read replay response: %v", err)
+	}
+
+	fmt.Printf("%s\n", body)
+}
+
+// CookieChangeType enumerates whether a cookie observed by --watch was added, modified, or
+// removed from the jar since the previous snapshot
+type CookieChangeType string
+
+const (
+	CookieAdded    CookieChangeType = "added"
+	CookieModified CookieChangeType = "modified"
+	CookieDeleted  CookieChangeType = "deleted"
+)
+
+// CookieChange is a single JSONL record streamed to stdout by --watch
+type CookieChange struct {
+	Type      CookieChangeType `json:"type"`
+	Timestamp string           `json:"timestamp"`
+	URL       string           `json:"url"`
+	Cookie    Cookie           `json:"cookie"`
+}
+
+// cookieKey identifies a cookie by its RFC 6265 identity (domain, path, name) so that changes
+// to its value/expiry/flags are reported as "modified" rather than a delete-then-add pair
+func cookieKey(c Cookie) string {
+	return c.Domain + "\x00" + c.Path + "\x00" + c.Name
+}
+
+// WatchCookies streams cookie diffs (added/modified/deleted) to stdout as JSONL, useful for
+// auth debugging and for capturing short-lived session tokens that disappear before a one-shot
+// dump can grab them. The Storage domain has no cookie-change event to subscribe to, so this
+// instead re-snapshots whenever a request/response pair crosses the wire, and falls back to a
+// one-second poll so changes made outside of network activity still surface. The common
+// (non-BrowserContextID) case reuses the single connection already open for the life of the
+// watch instead of going through GetCookies, which would otherwise redial for every tick.
+// -g narrows the stream to names/domains containing grep
+func WatchCookies(debugPort string, target DebugData, grep string) {
+	grepFlag := len(grep) > 0
+
+	client := dialTarget(target)
+	defer client.Close()
+
+	if _, err := client.Call("Network.enable", nil); err != nil {
+		log.Fatalf("Failed to enable Network domain: %v", err)
+	}
+
+	previous := map[string]Cookie{}
+
+	emit := func(changeType CookieChangeType, url string, cookie Cookie) {
+		if grepFlag && !strings.Contains(cookie.Name, grep) && !strings.Contains(cookie.Domain, grep) {
+			return
+		}
+
+		change := CookieChange{
+			Type:      changeType,
+			Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
+			URL:       url,
+			Cookie:    cookie,
+		}
+		changeJSON, err := json.Marshal(change)
+		if err != nil {
+			log.Fatalf("Failed to marshal cookie change: %v", err)
+		}
+		fmt.Println(string(changeJSON))
+	}
+
+	refresh := func(url string) {
+		var cookiesResult CookiesResult
+		if target.BrowserContextID != "" {
+			cookiesResult, _ = GetCookies(debugPort, target)
+		} else {
+			result, err := client.Call("Network.getAllCookies", nil)
+			if err != nil {
+				return
+			}
+			if err := json.Unmarshal(result, &cookiesResult); err != nil {
+				return
+			}
+		}
+
+		current := make(map[string]Cookie, len(cookiesResult.Cookies))
+		for _, cookie := range cookiesResult.Cookies {
+			key := cookieKey(cookie)
+			current[key] = cookie
+
+			if old, ok := previous[key]; !ok {
+				emit(CookieAdded, url, cookie)
+			} else if old != cookie {
+				emit(CookieModified, url, cookie)
+			}
+		}
+
+		for key, cookie := range previous {
+			if _, ok := current[key]; !ok {
+				emit(CookieDeleted, url, cookie)
+			}
+		}
+
+		previous = current
+	}
+
+	refresh(target.URL)
+
+	// requestURLs correlates the *ExtraInfo events (which carry no URL) back to the request's
+	// actual URL via requestId, populated from the plain requestWillBeSent event that precedes
+	// them. Entries are evicted once responseReceivedExtraInfo consumes them, so this only grows
+	// unbounded for requests that are cancelled or redirected before a response ever arrives;
+	// maxTrackedRequests is a blunt backstop against that slow leak, not a steady-state limit
+	requestURLs := map[string]string{}
+	const maxTrackedRequests = 4096
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case event, ok := <-client.Events:
+			if !ok {
+				return
+			}
+
+			var url string
+
+			switch event.Method {
+			case "Network.requestWillBeSent":
+				var params struct {
+					RequestID string `json:"requestId"`
+					Request   struct {
+						URL string `json:"url"`
+					} `json:"request"`
+				}
+				if err := json.Unmarshal(event.Params, &params); err == nil {
+					if len(requestURLs) >= maxTrackedRequests {
+						requestURLs = map[string]string{}
+					}
+					requestURLs[params.RequestID] = params.Request.URL
+				}
+				continue
+			case "Network.requestWillBeSentExtraInfo":
+				var params struct {
+					RequestID string `json:"requestId"`
+				}
+				if err := json.Unmarshal(event.Params, &params); err == nil {
+					url = requestURLs[params.RequestID]
+				}
+			case "Network.responseReceivedExtraInfo":
+				var params struct {
+					RequestID string `json:"requestId"`
+				}
+				if err := json.Unmarshal(event.Params, &params); err == nil {
+					url = requestURLs[params.RequestID]
+					delete(requestURLs, params.RequestID)
+				}
+			default:
+				continue
+			}
+
+			refresh(url)
+		case <-ticker.C:
+			refresh(target.URL)
+		}
 	}
 }
 
@@ -229,10 +771,20 @@ func main() {
 
 	debugPort := parser.String("p", "port", &argparse.Options{Required: true, Help: "{REQUIRED} - Debug port"})
 	dump := parser.String("d", "dump", &argparse.Options{Required: false, Help: "{ pages || cookies } - Dump open tabs/extensions or cookies"})
-	format := parser.String("f", "format", &argparse.Options{Required: false, Help: "{ raw || human || modified } - Format when dumping cookies"})
+	format := parser.String("f", "format", &argparse.Options{Required: false, Help: "{ raw || human || modified || netscape } - Format when dumping cookies"})
 	grep := parser.String("g", "grep", &argparse.Options{Required: false, Help: "Narrow scope of dumping to specific name/domain"})
 	load := parser.String("l", "load", &argparse.Options{Required: false, Help: "File name for cookies to load into browser"})
 	clear := parser.String("c", "clear", &argparse.Options{Required: false, Help: "Clear cookies before loading new cookies"})
+	deleteFlag := parser.Flag("", "delete", &argparse.Options{Required: false, Help: "Delete cookies via Network.deleteCookies; requires --name, optionally narrowed by --domain/--path/--url"})
+	name := parser.String("", "name", &argparse.Options{Required: false, Help: "Cookie name selector for --delete"})
+	domain := parser.String("", "domain", &argparse.Options{Required: false, Help: "Cookie domain selector for --delete"})
+	path := parser.String("", "path", &argparse.Options{Required: false, Help: "Cookie path selector for --delete"})
+	url := parser.String("", "url", &argparse.Options{Required: false, Help: "Cookie url selector for --delete"})
+	set := parser.String("", "set", &argparse.Options{Required: false, Help: "name=value;domain=...;path=...;expires=... - Set a single cookie via Network.setCookie"})
+	target := parser.String("", "target", &argparse.Options{Required: false, Help: "Target id to operate on, instead of the first target Chromium returns"})
+	targetURL := parser.String("", "target-url", &argparse.Options{Required: false, Help: "Substring match against a target's URL to select it, instead of the first target Chromium returns"})
+	replay := parser.String("", "replay", &argparse.Options{Required: false, Help: "URL to perform an authenticated GET against, using dumped cookies loaded into a net/http/cookiejar.Jar"})
+	watch := parser.Flag("", "watch", &argparse.Options{Required: false, Help: "Stream cookie added/modified/deleted diffs to stdout as JSONL; honors -g to filter by name/domain"})
 
 	err := parser.Parse(os.Args)
 	if err != nil {
@@ -247,17 +799,41 @@ func main() {
 		}
 		if *dump == "cookies" {
 			debugList := GetDebugData(*debugPort)
-			DumpCookies(debugList, *format, *grep)
+			DumpCookies(*debugPort, SelectTarget(debugList, *target, *targetURL), *format, *grep)
 		}
 	}
 
 	if *clear != "" {
 		debugList := GetDebugData(*debugPort)
-		ClearCookies(debugList)
+		ClearCookies(*debugPort, SelectTarget(debugList, *target, *targetURL))
+	}
+
+	if *deleteFlag {
+		if *name == "" {
+			log.Fatalf("--name is required for --delete; use --clear to wipe the whole jar instead")
+		}
+		debugList := GetDebugData(*debugPort)
+		selected := SelectTarget(debugList, *target, *targetURL)
+		DeleteCookies(selected, *name, *domain, *path, *url)
+	}
+
+	if *set != "" {
+		debugList := GetDebugData(*debugPort)
+		SetCookie(SelectTarget(debugList, *target, *targetURL), *set)
 	}
 
 	if *load != "" {
 		debugList := GetDebugData(*debugPort)
-		LoadCookies(debugList, *load)
+		LoadCookies(*debugPort, SelectTarget(debugList, *target, *targetURL), *load)
+	}
+
+	if *replay != "" {
+		debugList := GetDebugData(*debugPort)
+		ReplayCookies(*debugPort, SelectTarget(debugList, *target, *targetURL), *replay)
+	}
+
+	if *watch {
+		debugList := GetDebugData(*debugPort)
+		WatchCookies(*debugPort, SelectTarget(debugList, *target, *targetURL), *grep)
 	}
 }